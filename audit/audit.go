@@ -0,0 +1,81 @@
+// Package audit writes a JSONL trail of every ask_human call: enough to
+// reconstruct who asked what, when, through which backend, and how long it
+// took, without persisting question or answer content that a compromised
+// log sink could exfiltrate.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// maxHashInput bounds how much of a question or context string is hashed,
+// matching the repo's existing truncate-then-process approach elsewhere.
+const maxHashInput = 256
+
+// Record is one JSONL line describing a single ask_human call.
+type Record struct {
+	Timestamp     time.Time `json:"timestamp"`
+	QuestionID    string    `json:"question_id"`
+	QuestionHash  string    `json:"question_hash"`
+	ContextHash   string    `json:"context_hash,omitempty"`
+	Backend       string    `json:"backend,omitempty"`
+	LatencyMS     int64     `json:"latency_ms"`
+	AnswerLength  int       `json:"answer_length"`
+	TimedOut      bool      `json:"timed_out"`
+	ClientSession string    `json:"client_session,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// Logger appends Records as JSONL to a configured destination.
+type Logger struct {
+	mu sync.Mutex
+	w  io.Writer
+	c  io.Closer
+}
+
+// NewLogger opens path for appending and returns a Logger that writes JSONL
+// records to it. path == "-" logs to stdout instead of a file, for
+// containerized deployments that collect logs from there.
+func NewLogger(path string) (*Logger, error) {
+	if path == "-" {
+		return &Logger{w: os.Stdout}, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %q: %w", path, err)
+	}
+	return &Logger{w: f, c: f}, nil
+}
+
+// Log appends rec to the log as a single JSON line.
+func (l *Logger) Log(rec Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return json.NewEncoder(l.w).Encode(rec)
+}
+
+// Close releases the underlying file, if any (logging to stdout is a no-op).
+func (l *Logger) Close() error {
+	if l.c == nil {
+		return nil
+	}
+	return l.c.Close()
+}
+
+// HashQuestion truncates s and hashes it so audit logs can correlate calls
+// and flag unusually long/short questions without recording their content
+// verbatim; the content itself lives in the Q&A store when one is configured.
+func HashQuestion(s string) string {
+	if len(s) > maxHashInput {
+		s = s[:maxHashInput]
+	}
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AskBackend is implemented by every way of putting a question in front of a
+// human and waiting for their answer: the zenity dialog, a terminal prompt,
+// the built-in web UI, Slack, or a generic webhook.
+type AskBackend interface {
+	AskQuestion(ctx context.Context, questionID, question, contextInfo string) (string, error)
+
+	// Name identifies the backend in logs and audit records, e.g. "zenity".
+	// It matches the name used to select it in cfg.Backend.
+	Name() string
+}
+
+// httpRegistrar is implemented by backends (web, webhook) that need their own
+// endpoints on the HTTP mux to receive answers out of band.
+type httpRegistrar interface {
+	RegisterHandlers(mux *http.ServeMux)
+}
+
+// closer is implemented by backends (the file backend's Watcher/TailReader)
+// that hold resources needing explicit shutdown.
+type closer interface {
+	Close() error
+}
+
+// NewBackends builds the ordered chain of AskBackend configured by
+// cfg.Backend, a comma-separated list such as "zenity,web". askQuestion tries
+// each in order and falls back to the next on error, so e.g. zenity (which
+// fails immediately on a headless box) can fall back to the web UI.
+func NewBackends(cfg *Config) ([]AskBackend, error) {
+	names := strings.Split(cfg.Backend, ",")
+	backends := make([]AskBackend, 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		switch name {
+		case "zenity":
+			backends = append(backends, NewZenityHandler(cfg.Timeout))
+		case "terminal":
+			backends = append(backends, NewTerminalBackend())
+		case "web":
+			backends = append(backends, NewWebBackend())
+		case "slack":
+			backend, err := NewSlackBackend(cfg)
+			if err != nil {
+				return nil, err
+			}
+			backends = append(backends, backend)
+		case "webhook":
+			backend, err := NewWebhookBackend(cfg)
+			if err != nil {
+				return nil, err
+			}
+			backends = append(backends, backend)
+		case "file":
+			backend, err := NewFileBackend(cfg)
+			if err != nil {
+				return nil, err
+			}
+			backends = append(backends, backend)
+		default:
+			return nil, fmt.Errorf("unknown ask backend %q", name)
+		}
+	}
+
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("no ask backend configured")
+	}
+
+	return backends, nil
+}
+
+// registerBackendHandlers wires any backend-provided HTTP endpoints (web,
+// webhook) into mux. It is a no-op for backends with no HTTP surface.
+func registerBackendHandlers(backends []AskBackend, mux *http.ServeMux) {
+	for _, b := range backends {
+		if registrar, ok := b.(httpRegistrar); ok {
+			registrar.RegisterHandlers(mux)
+		}
+	}
+}
+
+// closeBackends releases any resources held by backends that need explicit
+// shutdown (currently just the file backend's Watcher/TailReader),
+// returning the first error encountered, if any.
+func closeBackends(backends []AskBackend) error {
+	var firstErr error
+	for _, b := range backends {
+		if c, ok := b.(closer); ok {
+			if err := c.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
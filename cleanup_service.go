@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// cleanupService periodically purges timed-out pending questions. It is the
+// supervised replacement for the old ad hoc `go cleanupLoop()`.
+type cleanupService struct {
+	server *AskHumanServer
+}
+
+// Serve implements Service.
+func (c *cleanupService) Serve(ctx context.Context) error {
+	ticker := time.NewTicker(c.server.config.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.server.cleanupTimeouts()
+		}
+	}
+}
+
+// cleanupTimeouts removes questions that have passed their deadline,
+// canceling each one's context with ErrQuestionTimedOut as the cause so the
+// goroutine waiting on its backend chain wakes up with a distinguishable
+// error instead of hanging until the client gives up.
+func (s *AskHumanServer) cleanupTimeouts() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for questionID, state := range s.pendingQuestions {
+		if now.After(state.deadline) {
+			if state.cancel != nil {
+				state.cancel(ErrQuestionTimedOut)
+			}
+			delete(s.pendingQuestions, questionID)
+		}
+	}
+}
@@ -6,26 +6,48 @@ import (
 
 // Config holds configuration for the Ask-Human MCP server
 type Config struct {
-	Timeout             time.Duration // Question timeout
-	MaxQuestionLength   int           // Maximum question length in bytes
-	MaxContextLength    int           // Maximum context length in bytes
-	MaxPendingQuestions int           // Maximum concurrent pending questions
-	CleanupInterval     time.Duration // Cleanup interval for timeouts
-	Host                string        // HTTP server host
-	Port                int           // HTTP server port
-	HTTPMode            bool          // Whether to run in HTTP mode
+	Timeout              time.Duration // Question timeout
+	MaxQuestionLength    int           // Maximum question length in bytes
+	MaxContextLength     int           // Maximum context length in bytes
+	MaxPendingQuestions  int           // Maximum concurrent pending questions
+	CleanupInterval      time.Duration // Cleanup interval for timeouts
+	NotificationInterval time.Duration // Interval between progress/ping keepalives for a pending question
+	Host                 string        // HTTP server host
+	Port                 int           // HTTP server port
+	HTTPMode             bool          // Whether to run in HTTP mode
+	Transport            string        // "stdio", "sse", or "streamable"
+	StorePath            string        // Path to the persistent Q&A history store; empty disables it
+	StoreBackend         string        // "auto", "markdown", or "sqlite"
+	Backend              string        // Comma-separated AskBackend chain, e.g. "zenity,web"
+	SlackToken           string        // Bot token for the slack backend
+	SlackChannel         string        // Channel ID the slack backend posts questions to
+	WebhookURL           string        // URL the webhook backend POSTs questions to
+	WebhookCallbackBase  string        // This server's externally reachable base URL, for webhook callbacks
+	AuditLogPath         string        // Path for JSONL call audit logs; "-" for stdout; empty disables it
+	WatcherMode          string        // "auto", "fsnotify", or "poll" for watching the answers file
+	WatcherPollInterval  time.Duration // Poll interval used by the poll watcher (and by "auto" on fallback)
+	FileAnswersPath      string        // Markdown answers file watched by the "file" backend; required to select it
 }
 
 // DefaultConfig returns a default configuration
 func DefaultConfig() *Config {
 	return &Config{
-		Timeout:             30 * time.Minute,
-		MaxQuestionLength:   10240, // 10KB
-		MaxContextLength:    51200, // 50KB
-		MaxPendingQuestions: 100,
-		CleanupInterval:     5 * time.Minute,
-		Host:                "localhost",
-		Port:                3000,
-		HTTPMode:            false,
+		Timeout:              30 * time.Minute,
+		MaxQuestionLength:    10240, // 10KB
+		MaxContextLength:     51200, // 50KB
+		MaxPendingQuestions:  100,
+		CleanupInterval:      5 * time.Minute,
+		NotificationInterval: 30 * time.Second,
+		Host:                 "localhost",
+		Port:                 3000,
+		HTTPMode:             false,
+		Transport:            "stdio",
+		StorePath:            "",
+		StoreBackend:         "auto",
+		Backend:              "zenity",
+		AuditLogPath:         "",
+		WatcherMode:          "auto",
+		WatcherPollInterval:  200 * time.Millisecond,
+		FileAnswersPath:      "",
 	}
 }
@@ -0,0 +1,27 @@
+package main
+
+import "errors"
+
+// Sentinel errors returned by the validation, locking, and storage layers so
+// callers can distinguish failure modes with errors.Is instead of string
+// matching.
+var (
+	// ErrInputValidation indicates a question or context value failed validation.
+	ErrInputValidation = errors.New("input validation failed")
+
+	// ErrAnswerNotFound indicates a store has no (answered) record for a question ID.
+	ErrAnswerNotFound = errors.New("answer not found")
+
+	// ErrLockTimeout indicates an advisory file lock could not be acquired in time.
+	ErrLockTimeout = errors.New("file lock timed out")
+
+	// ErrQuestionTimedOut indicates a question's deadline elapsed before a
+	// human answered it, as opposed to the caller (or MCP client) canceling
+	// the request itself.
+	ErrQuestionTimedOut = errors.New("question timed out waiting for an answer")
+
+	// ErrServerClosing indicates a question was still pending when Close was
+	// called, so its context was canceled to unblock the goroutines waiting
+	// on it instead of leaving them running past shutdown.
+	ErrServerClosing = errors.New("server is closing")
+)
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// answerRecordPattern matches one "### <questionID>" record in the markdown
+// answers file and captures its ID and answer text, mirroring the layout
+// AppendQuestion writes and UpdateAnswer edits in fileutils.go.
+var answerRecordPattern = regexp.MustCompile(`(?is)### (\S+)\s*\n.*?\*\*Answer:\*\*\s*(.*?)(?:\n{2,}---|### |$)`)
+
+// parseAnswerRecords extracts every resolved (non-PENDING) answer record
+// from markdown answers file content. AppendQuestion and UpdateAnswer
+// (fileutils.go) both rewrite the whole file via a temp-file-plus-rename,
+// so TailReader always sees a full, self-consistent snapshot rather than a
+// true incremental append — there's never a partial trailing record to
+// carry over, so this always consumes the entire input.
+func parseAnswerRecords(data []byte) ([]Answer, int, error) {
+	matches := answerRecordPattern.FindAllStringSubmatch(string(data), -1)
+
+	var answers []Answer
+	for _, m := range matches {
+		answer := strings.TrimSpace(m[2])
+		if answer == "" || strings.EqualFold(answer, "PENDING") {
+			continue
+		}
+		answers = append(answers, Answer{QuestionID: m[1], Text: answer})
+	}
+
+	return answers, len(data), nil
+}
+
+// FileBackend answers questions by appending them to a markdown file (the
+// same format MarkdownStore reads and writes) and waiting for a human to
+// fill in the answer directly in that file, instead of a zenity dialog,
+// terminal prompt, or web UI. It watches the file with a Watcher and reads
+// it with a TailReader so a burst of edits only costs one incremental
+// parse instead of a full re-scan per notification.
+type FileBackend struct {
+	path    string
+	watcher Watcher
+	tail    *TailReader
+}
+
+// NewFileBackend creates a FileBackend watching cfg.FileAnswersPath.
+func NewFileBackend(cfg *Config) (*FileBackend, error) {
+	if cfg.FileAnswersPath == "" {
+		return nil, fmt.Errorf("file backend requires FileAnswersPath")
+	}
+
+	watcher, err := NewWatcher(cfg.FileAnswersPath, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch %s: %w", cfg.FileAnswersPath, err)
+	}
+
+	return &FileBackend{
+		path:    cfg.FileAnswersPath,
+		watcher: watcher,
+		tail:    NewTailReader(cfg.FileAnswersPath, parseAnswerRecords),
+	}, nil
+}
+
+// AskQuestion implements AskBackend.
+func (f *FileBackend) AskQuestion(ctx context.Context, questionID, question, contextInfo string) (string, error) {
+	if err := AppendQuestion(f.path, questionID, question, contextInfo, time.Now().Format(time.RFC3339)); err != nil {
+		return "", fmt.Errorf("failed to record question in %s: %w", f.path, err)
+	}
+
+	changes := f.watcher.RegisterCallback(questionID)
+	defer f.watcher.UnregisterCallback(questionID)
+
+	answers := f.tail.RegisterCallback(questionID)
+	defer f.tail.UnregisterCallback(questionID)
+
+	// The answer might already be there if it landed between AppendQuestion
+	// and RegisterCallback; do one read up front instead of only on change.
+	if err := f.tail.OnChange(); err != nil {
+		log.Printf("file backend: initial read of %s failed: %v", f.path, err)
+	}
+
+	for {
+		select {
+		case ans := <-answers:
+			return ans.Text, nil
+
+		case ev := <-changes:
+			if ev.Overflowed {
+				// One or more notifications were coalesced while we
+				// weren't looking; the tail reader's offset can no longer
+				// be trusted to have seen every intermediate write, so
+				// force it to re-scan the whole file from scratch.
+				f.tail.Reset()
+			}
+			if err := f.tail.OnChange(); err != nil {
+				log.Printf("file backend: read of %s failed: %v", f.path, err)
+			}
+
+		case <-ctx.Done():
+			return "", context.Cause(ctx)
+		}
+	}
+}
+
+// Name implements AskBackend.
+func (f *FileBackend) Name() string {
+	return "file"
+}
+
+// Close releases the watcher and tail reader's underlying resources.
+func (f *FileBackend) Close() error {
+	tailErr := f.tail.Close()
+	if err := f.watcher.Close(); err != nil {
+		return err
+	}
+	return tailErr
+}
+
+// Stats reports the underlying Watcher's notification delivery counters.
+func (f *FileBackend) Stats() WatcherStats {
+	return f.watcher.Stats()
+}
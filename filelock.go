@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// lockAcquireTimeout bounds how long acquire retries a non-blocking lock
+// attempt before giving up with ErrLockTimeout, so a wedged holder (e.g. a
+// process stuck on a hung NFS mount) can't stall a caller forever.
+const lockAcquireTimeout = 10 * time.Second
+
+// lockPollInterval is how long acquire sleeps between non-blocking attempts.
+const lockPollInterval = 50 * time.Millisecond
+
+// FileLock provides cross-platform advisory file locking backed by the OS
+// (flock(2) on Unix, LockFileEx on Windows). Unlike a sidecar ".lock" file
+// created with O_EXCL, an OS advisory lock is automatically released by the
+// kernel when the holding process dies, so a crash can never leave a stale
+// lock behind. RLock/Lock mirror sync.RWMutex semantics: readers (FindAnswer)
+// may hold the lock concurrently with each other, writers (AppendQuestion)
+// need it exclusively.
+type FileLock struct {
+	filePath string
+	file     *os.File
+	mutex    sync.Mutex
+}
+
+// NewFileLock creates a new file lock for the given path. The lock is taken
+// on a sidecar "<path>.lock" file so the protected file itself can be
+// replaced via atomic rename (see SafeWriteText) without disturbing the lock.
+func NewFileLock(filePath string) *FileLock {
+	return &FileLock{
+		filePath: filePath,
+	}
+}
+
+// Lock acquires an exclusive (write) lock, retrying until it is available or
+// lockAcquireTimeout elapses, whichever comes first.
+func (fl *FileLock) Lock() error {
+	return fl.acquire(true)
+}
+
+// RLock acquires a shared (read) lock, retrying until it is available or
+// lockAcquireTimeout elapses, whichever comes first. Multiple readers may
+// hold an RLock at the same time.
+func (fl *FileLock) RLock() error {
+	return fl.acquire(false)
+}
+
+func (fl *FileLock) acquire(exclusive bool) error {
+	fl.mutex.Lock()
+	defer fl.mutex.Unlock()
+
+	lockPath := fl.filePath + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(lockAcquireTimeout)
+	for {
+		ok, err := tryLockFile(file, exclusive)
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return fmt.Errorf("%w: %s", ErrLockTimeout, lockPath)
+		}
+		time.Sleep(lockPollInterval)
+	}
+
+	fl.file = file
+	return nil
+}
+
+// Unlock releases the file lock, however it was acquired.
+func (fl *FileLock) Unlock() error {
+	fl.mutex.Lock()
+	defer fl.mutex.Unlock()
+
+	if fl.file == nil {
+		return nil
+	}
+
+	err := unlockFile(fl.file)
+	closeErr := fl.file.Close()
+	fl.file = nil
+
+	if err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return closeErr
+}
+
+// WithFileLock executes fn while holding an exclusive lock on filePath.
+func WithFileLock(filePath string, fn func() error) error {
+	lock := NewFileLock(filePath)
+	if err := lock.Lock(); err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	return fn()
+}
+
+// WithReadFileLock executes fn while holding a shared lock on filePath,
+// allowing other readers (but no writer) to run concurrently.
+func WithReadFileLock(filePath string, fn func() error) error {
+	lock := NewFileLock(filePath)
+	if err := lock.RLock(); err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	return fn()
+}
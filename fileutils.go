@@ -6,68 +6,9 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
-	"sync"
 	"unicode"
 )
 
-// FileLock provides cross-platform file locking
-type FileLock struct {
-	filePath string
-	lockFile *os.File
-	mutex    sync.Mutex
-}
-
-// NewFileLock creates a new file lock for the given path
-func NewFileLock(filePath string) *FileLock {
-	return &FileLock{
-		filePath: filePath,
-	}
-}
-
-// Lock acquires the file lock
-func (fl *FileLock) Lock() error {
-	fl.mutex.Lock()
-	defer fl.mutex.Unlock()
-
-	lockPath := fl.filePath + ".lock"
-	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to acquire lock: %w", err)
-	}
-
-	fl.lockFile = file
-	return nil
-}
-
-// Unlock releases the file lock
-func (fl *FileLock) Unlock() error {
-	fl.mutex.Lock()
-	defer fl.mutex.Unlock()
-
-	if fl.lockFile == nil {
-		return nil
-	}
-
-	err := fl.lockFile.Close()
-	fl.lockFile = nil
-
-	lockPath := fl.filePath + ".lock"
-	os.Remove(lockPath) // Best effort cleanup
-
-	return err
-}
-
-// WithFileLock executes a function with file lock protection
-func WithFileLock(filePath string, fn func() error) error {
-	lock := NewFileLock(filePath)
-	if err := lock.Lock(); err != nil {
-		return err
-	}
-	defer lock.Unlock()
-
-	return fn()
-}
-
 // ValidateInput validates and sanitizes input text
 func ValidateInput(text string, maxLength int, fieldName string) (string, error) {
 	if len(text) > maxLength {
@@ -177,6 +118,37 @@ func FindAnswer(content, questionID string) (string, bool) {
 	return "", false
 }
 
+// UpdateAnswer rewrites the PENDING answer for questionID to answer. It is a
+// no-op (returns false, nil) if the question section can't be found.
+func UpdateAnswer(filePath, questionID, answer string) (bool, error) {
+	var updated bool
+	err := WithFileLock(filePath, func() error {
+		content, err := SafeReadText(filePath)
+		if err != nil {
+			return err
+		}
+
+		pattern := fmt.Sprintf(`(?is)### %s\s*\n.*?\*\*Answer:\*\*\s*`, regexp.QuoteMeta(questionID))
+		re := regexp.MustCompile(pattern)
+		loc := re.FindStringIndex(content)
+		if loc == nil {
+			return nil
+		}
+
+		// Replace everything between the end of "**Answer:**" and the next
+		// record delimiter (blank-line-then-"---", next "### ", or EOF).
+		rest := content[loc[1]:]
+		endPattern := regexp.MustCompile(`(?is)\n{2,}---|### |$`)
+		endLoc := endPattern.FindStringIndex(rest)
+		tail := rest[endLoc[0]:]
+
+		newContent := content[:loc[1]] + answer + tail
+		updated = true
+		return SafeWriteText(filePath, newContent)
+	})
+	return updated, err
+}
+
 // AppendQuestion adds a new question to the markdown file
 func AppendQuestion(filePath, questionID, question, context, timestamp string) error {
 	return WithFileLock(filePath, func() error {
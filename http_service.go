@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// httpService wraps http.Server as a Service so HTTP mode is supervised
+// exactly like cleanup and progress notifications, instead of a bare
+// goroutine whose shutdown Close() didn't wait for. listener is normally
+// obtained via ListenWithActivation so socket-activated or graceful-restart
+// sockets are reused instead of rebinding.
+type httpService struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+// Serve implements Service.
+func (h *httpService) Serve(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		if err := h.server.Serve(h.listener); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+			return
+		}
+		errCh <- nil
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return h.server.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return err
+	}
+}
+
+// sseService shuts the MCP SSE transport down in lockstep with httpService.
+type sseService struct {
+	sse *server.SSEServer
+}
+
+// Serve implements Service.
+func (s *sseService) Serve(ctx context.Context) error {
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return s.sse.Shutdown(shutdownCtx)
+}
+
+// streamableService shuts the MCP Streamable HTTP transport down in
+// lockstep with httpService; only registered when that transport is in use.
+type streamableService struct {
+	streamable *server.StreamableHTTPServer
+}
+
+// Serve implements Service.
+func (s *streamableService) Serve(ctx context.Context) error {
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return s.streamable.Shutdown(shutdownCtx)
+}
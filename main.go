@@ -1,7 +1,6 @@
 package main
 
 import (
-	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -9,7 +8,6 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
 	"syscall"
 	"time"
 
@@ -19,15 +17,26 @@ import (
 func main() {
 	// Parse command line flags
 	var (
-		helpFlag       = flag.Bool("help", false, "Show help message")
-		httpMode       = flag.Bool("http", false, "Run in HTTP mode instead of stdio")
-		host           = flag.String("host", "localhost", "HTTP server host")
-		port           = flag.Int("port", 3000, "HTTP server port")
-		timeoutFlag    = flag.Int("timeout", 1800, "Question timeout in seconds")
-		maxPending     = flag.Int("max-pending", 100, "Maximum pending questions")
-		maxQuestionLen = flag.Int("max-question-length", 10240, "Maximum question length")
-		maxContextLen  = flag.Int("max-context-length", 51200, "Maximum context length")
-		verbose        = flag.Bool("verbose", false, "Enable verbose logging (not recommended for stdio mode)")
+		helpFlag        = flag.Bool("help", false, "Show help message")
+		httpMode        = flag.Bool("http", false, "Run in HTTP mode instead of stdio")
+		host            = flag.String("host", "localhost", "HTTP server host")
+		port            = flag.Int("port", 3000, "HTTP server port")
+		timeoutFlag     = flag.Int("timeout", 1800, "Question timeout in seconds")
+		maxPending      = flag.Int("max-pending", 100, "Maximum pending questions")
+		maxQuestionLen  = flag.Int("max-question-length", 10240, "Maximum question length")
+		maxContextLen   = flag.Int("max-context-length", 51200, "Maximum context length")
+		verbose         = flag.Bool("verbose", false, "Enable verbose logging (not recommended for stdio mode)")
+		storePath       = flag.String("store-path", "", "Path to a persistent Q&A history store (enables sharing history across instances)")
+		storeBackend    = flag.String("store-backend", "auto", "Q&A store backend: auto, markdown, or sqlite")
+		backend         = flag.String("backend", "zenity", "Comma-separated ask backend chain, tried in order (zenity, terminal, web, slack, webhook, file)")
+		slackToken      = flag.String("slack-token", "", "Slack bot token, for --backend slack")
+		slackChannel    = flag.String("slack-channel", "", "Slack channel ID, for --backend slack")
+		webhookURL      = flag.String("webhook-url", "", "Webhook URL to POST questions to, for --backend webhook")
+		webhookBase     = flag.String("webhook-callback-base", "", "This server's externally reachable base URL, for --backend webhook")
+		fileAnswersPath = flag.String("file-answers-path", "", "Markdown answers file to watch, for --backend file")
+		auditLogPath    = flag.String("audit-log-path", "", "Path for JSONL call audit logs; use \"-\" for stdout (default: disabled)")
+		vulnStrict      = flag.Bool("vuln-check-strict", false, "Refuse to start instead of warning if a known-vulnerable dependency is detected")
+		transport       = flag.String("transport", "", "Transport: stdio, sse, or streamable (default: stdio, or sse if --http is set)")
 	)
 
 	flag.Parse()
@@ -37,21 +46,53 @@ func main() {
 		return
 	}
 
+	// Resolve the transport: --transport takes precedence; otherwise fall
+	// back to the pre-existing --http toggle for backward compatibility.
+	transportMode := *transport
+	if transportMode == "" {
+		if *httpMode {
+			transportMode = "sse"
+		} else {
+			transportMode = "stdio"
+		}
+	}
+	switch transportMode {
+	case "stdio", "sse", "streamable":
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown --transport %q (want stdio, sse, or streamable)\n", transportMode)
+		os.Exit(1)
+	}
+
 	// In stdio mode, disable logging to stderr to avoid interfering with MCP protocol
 	// unless verbose mode is explicitly enabled
-	if !*httpMode && !*verbose {
+	if transportMode == "stdio" && !*verbose {
 		log.SetOutput(io.Discard)
 	}
 
 	// Create configuration
 	config := DefaultConfig()
-	config.HTTPMode = *httpMode
+	config.Transport = transportMode
+	config.HTTPMode = transportMode != "stdio"
 	config.Host = *host
 	config.Port = *port
 	config.Timeout = time.Duration(*timeoutFlag) * time.Second
 	config.MaxPendingQuestions = *maxPending
 	config.MaxQuestionLength = *maxQuestionLen
 	config.MaxContextLength = *maxContextLen
+	config.StorePath = *storePath
+	config.StoreBackend = *storeBackend
+	config.Backend = *backend
+	config.SlackToken = *slackToken
+	config.SlackChannel = *slackChannel
+	config.WebhookURL = *webhookURL
+	config.WebhookCallbackBase = *webhookBase
+	config.FileAnswersPath = *fileAnswersPath
+	config.AuditLogPath = *auditLogPath
+
+	if err := CheckVulnerabilities(*vulnStrict); err != nil {
+		fmt.Fprintf(os.Stderr, "Refusing to start: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Create server
 	askServer, err := NewAskHumanServer(config)
@@ -72,8 +113,8 @@ func main() {
 			askServer.Close()
 		}()
 
-		log.Printf("Ask-Human MCP Server starting in HTTP mode on %s:%d", config.Host, config.Port)
-		if err := runHTTPMode(askServer, config.Host, config.Port); err != nil {
+		log.Printf("Ask-Human MCP Server starting in HTTP mode (%s transport) on %s:%d", config.Transport, config.Host, config.Port)
+		if err := runHTTPMode(askServer, config.Host, config.Port, config.Transport); err != nil {
 			fmt.Fprintf(os.Stderr, "HTTP server error: %v\n", err)
 			os.Exit(1)
 		}
@@ -97,8 +138,13 @@ func runStdioMode(askServer *AskHumanServer) error {
 	return server.ServeStdio(mcpServer)
 }
 
-// runHTTPMode runs the server in HTTP/SSE mode
-func runHTTPMode(askServer *AskHumanServer, host string, port int) error {
+// runHTTPMode runs the server in HTTP mode. The SSE transport is always
+// mounted for backward compatibility; the streamable HTTP transport (the
+// spec's replacement for SSE) is additionally mounted at /mcp when
+// transport is "streamable". Both transports are registered with
+// askServer's supervisor so they share its restart and deterministic-
+// shutdown behavior instead of being started ad hoc.
+func runHTTPMode(askServer *AskHumanServer, host string, port int, transport string) error {
 	mcpServer := askServer.GetMCPServer()
 
 	// Create SSE server
@@ -111,6 +157,11 @@ func runHTTPMode(askServer *AskHumanServer, host string, port int) error {
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
+		if stats, ok := askServer.WatcherStats(); ok {
+			fmt.Fprintf(w, `{"status":"ok","service":"ask-human-mcp","watcher":{"delivered":%d,"coalesced":%d,"overflowed":%d}}`,
+				stats.Delivered, stats.Coalesced, stats.Overflowed)
+			return
+		}
 		fmt.Fprintf(w, `{"status":"ok","service":"ask-human-mcp"}`)
 	})
 
@@ -120,37 +171,41 @@ func runHTTPMode(askServer *AskHumanServer, host string, port int) error {
 	// Message endpoint for MCP communication
 	mux.Handle("/message", sseServer.MessageHandler())
 
-	httpServer := &http.Server{
-		Addr:    host + ":" + strconv.Itoa(port),
-		Handler: mux,
+	var streamableServer *server.StreamableHTTPServer
+	if transport == "streamable" {
+		streamableServer = server.NewStreamableHTTPServer(mcpServer)
+		mux.Handle("/mcp", streamableServer)
 	}
 
-	// Start server in goroutine
-	errChan := make(chan error, 1)
-	go func() {
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errChan <- err
-		}
-	}()
+	// Backend-provided endpoints (the web backend's /ask UI, the webhook
+	// backend's /answer/{id} callback)
+	askServer.RegisterHTTPHandlers(mux)
+
+	listener, err := ListenWithActivation(host, port)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s:%d: %w", host, port, err)
+	}
+
+	httpServer := &http.Server{Handler: mux}
+
+	askServer.supervisor.Go("http", &httpService{server: httpServer, listener: listener})
+	askServer.supervisor.Go("sse", &sseService{sse: sseServer})
+	if streamableServer != nil {
+		askServer.supervisor.Go("streamable", &streamableService{streamable: streamableServer})
+	}
+	askServer.supervisor.Go("graceful-restart", NewGracefulRestarter(askServer, listener))
 
 	log.Printf("Server listening on http://%s:%d", host, port)
 	log.Printf("SSE endpoint: http://%s:%d/sse", host, port)
+	if streamableServer != nil {
+		log.Printf("Streamable HTTP endpoint: http://%s:%d/mcp", host, port)
+	}
 	log.Printf("Health check: http://%s:%d/health", host, port)
 
-	// Wait for shutdown signal or error
-	select {
-	case <-askServer.shutdownCtx.Done():
-		// Graceful shutdown
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer shutdownCancel()
-
-		if err := sseServer.Shutdown(shutdownCtx); err != nil {
-			log.Printf("SSE server shutdown error: %v", err)
-		}
-		return httpServer.Shutdown(shutdownCtx)
-	case err := <-errChan:
-		return err
-	}
+	// Block until Close() (triggered by a signal or an explicit shutdown)
+	// cancels the supervisor; Close() itself waits for http/sse to finish.
+	<-askServer.shutdownCtx.Done()
+	return nil
 }
 
 // showHelp displays usage information
@@ -165,7 +220,8 @@ USAGE:
 
 OPTIONS:
     --help                      Show this help message
-    --http                      Run in HTTP mode instead of stdio
+    --http                      Run in HTTP mode instead of stdio (same as --transport sse)
+    --transport <MODE>          stdio, sse, or streamable (default: stdio, or sse if --http is set)
     --host <HOST>               HTTP server host (default: localhost)
     --port <PORT>               HTTP server port (default: 3000)
     --timeout <SECONDS>         Question timeout in seconds (default: 1800)
@@ -173,14 +229,28 @@ OPTIONS:
     --max-question-length <NUM> Maximum question length (default: 10240)
     --max-context-length <NUM>  Maximum context length (default: 51200)
     --verbose                   Enable verbose logging (not recommended for stdio mode)
+    --store-path <PATH>         Persistent Q&A history store path (default: disabled)
+    --store-backend <BACKEND>   Q&A store backend: auto, markdown, or sqlite (default: auto)
+    --backend <CHAIN>           Comma-separated ask backend chain (default: zenity)
+                                 Options: zenity, terminal, web, slack, webhook, file
+    --slack-token <TOKEN>       Slack bot token, for --backend slack
+    --slack-channel <ID>        Slack channel ID, for --backend slack
+    --webhook-url <URL>         Webhook URL to POST questions to, for --backend webhook
+    --webhook-callback-base <URL> This server's externally reachable base URL, for --backend webhook
+    --file-answers-path <PATH>  Markdown answers file to watch, for --backend file
+    --audit-log-path <PATH>     JSONL call audit log path, or "-" for stdout (default: disabled)
+    --vuln-check-strict         Refuse to start instead of warning on a known-vulnerable dependency
 
 EXAMPLES:
     # Run in stdio mode (for MCP clients like Cursor)
     ask-human-go
 
-    # Run in HTTP mode
+    # Run in HTTP mode (SSE transport, for backward compatibility)
     ask-human-go --http --port 3000
 
+    # Run in HTTP mode with the Streamable HTTP transport
+    ask-human-go --transport streamable --port 3000
+
     # Run with custom timeout
     ask-human-go --timeout 900
 
@@ -195,7 +265,7 @@ For Cursor (.cursor/mcp.json):
       }
     }
 
-For HTTP mode:
+For HTTP mode (SSE transport):
     {
       "mcpServers": {
         "ask-human": {
@@ -204,6 +274,15 @@ For HTTP mode:
       }
     }
 
+For HTTP mode (Streamable HTTP transport):
+    {
+      "mcpServers": {
+        "ask-human": {
+          "url": "http://localhost:3000/mcp"
+        }
+      }
+    }
+
 WORKFLOW:
 1. AI agent calls ask_human(question, context)
 2. A GUI dialog box appears asking the question
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// progressNotifier is the supervised form of the periodic "still waiting"
+// notification sent while a question is pending, keeping the MCP client's
+// connection alive without it timing out. One is spawned per in-flight
+// question via Supervisor.GoOnce and stops when that question's context ends.
+//
+// Per the MCP spec, progress notifications are only valid once the client
+// has opted in by sending a progressToken with its request; sending them
+// unprompted with a fabricated token is spec-non-compliant and some clients
+// reject it outright. When the client didn't provide one, this falls back
+// to MCP ping keepalives instead.
+type progressNotifier struct {
+	progressToken mcp.ProgressToken // nil if the client didn't request progress updates
+	questionID    string
+	interval      time.Duration
+}
+
+// Serve implements Service.
+func (p *progressNotifier) Serve(ctx context.Context) error {
+	// Get client session from context to send notifications
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || !session.Initialized() {
+		// No session available, cannot send progress notifications
+		return nil
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	startTime := time.Now()
+	notificationCount := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			notificationCount++
+
+			notif := p.pingNotification()
+			if p.progressToken != nil {
+				notif = p.progressNotification(notificationCount, time.Since(startTime))
+			}
+
+			// Send notification through session channel (non-blocking)
+			select {
+			case session.NotificationChannel() <- notif:
+				// Notification sent successfully
+			default:
+				// Channel full or closed, stop sending notifications
+				return nil
+			}
+		}
+	}
+}
+
+// progressNotification builds a notifications/progress message reporting
+// elapsed wait time, only ever sent when the client gave us a token for it.
+func (p *progressNotifier) progressNotification(count int, elapsed time.Duration) mcp.JSONRPCNotification {
+	message := fmt.Sprintf("Waiting for human response... (%s elapsed)", elapsed.Round(time.Second))
+
+	// Create progress notification using the helper function
+	progressNotif := mcp.NewProgressNotification(p.progressToken, float64(count), nil, &message)
+
+	// Create JSONRPCNotification with the progress params
+	// We need to manually construct this because ProgressNotification has its own Params type
+	return mcp.JSONRPCNotification{
+		JSONRPC: "2.0",
+		Notification: mcp.Notification{
+			Method: progressNotif.Notification.Method,
+			Params: mcp.NotificationParams{
+				AdditionalFields: map[string]any{
+					"progressToken": p.progressToken,
+					"progress":      float64(count),
+					"message":       message,
+				},
+			},
+		},
+	}
+}
+
+// pingNotification builds a bare MCP ping, used to keep the transport alive
+// when the client never asked for progress updates. mcp-go's ClientSession
+// only exposes notification delivery (no server-initiated requests awaiting
+// a reply), so this is a notification-shaped ping rather than the
+// request/response ping the spec describes — it still resets any idle
+// timeout on the connection, which is all we need it for here.
+func (p *progressNotifier) pingNotification() mcp.JSONRPCNotification {
+	return mcp.JSONRPCNotification{
+		JSONRPC: "2.0",
+		Notification: mcp.Notification{
+			Method: string(mcp.MethodPing),
+		},
+	}
+}
@@ -0,0 +1,170 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	envListenFDs       = "LISTEN_FDS" // systemd socket-activation convention
+	envListenPID       = "LISTEN_PID" // systemd socket-activation convention
+	envGracefulRestart = "ASK_HUMAN_GO_RESTART"
+
+	listenerFD = 3 // first inherited fd, by either convention above
+	snapshotFD = 4 // second inherited fd: our own in-flight question snapshot
+)
+
+// PendingQuestionSnapshot is the serialized form of one in-flight question,
+// carried across a graceful restart so the new process can re-register it
+// instead of silently dropping it.
+type PendingQuestionSnapshot struct {
+	ID        string    `json:"id"`
+	Question  string    `json:"question"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// ListenWithActivation returns a listener for host:port, reusing the
+// systemd/launchd-activated socket on fd 3 when LISTEN_FDS/LISTEN_PID say one
+// was handed to this process, and otherwise binding a fresh listener.
+func ListenWithActivation(host string, port int) (net.Listener, error) {
+	if l := activatedListener(); l != nil {
+		return l, nil
+	}
+	return net.Listen("tcp", fmt.Sprintf("%s:%d", host, port))
+}
+
+// activatedListener returns the socket on fd 3 if this process was handed
+// one, either via systemd socket activation (LISTEN_FDS set and LISTEN_PID
+// matching our own pid, per the systemd convention) or via our own graceful
+// restart (ASK_HUMAN_GO_RESTART=1, set by the parent we were re-exec'd from;
+// we can't know our own pid ahead of exec to satisfy LISTEN_PID the way
+// systemd does, so that path uses its own marker instead).
+func activatedListener() net.Listener {
+	systemdActivated := func() bool {
+		nfds, _ := strconv.Atoi(os.Getenv(envListenFDs))
+		if nfds < 1 {
+			return false
+		}
+		pid, err := strconv.Atoi(os.Getenv(envListenPID))
+		return err == nil && pid == os.Getpid()
+	}
+
+	if !systemdActivated() && os.Getenv(envGracefulRestart) != "1" {
+		return nil
+	}
+
+	l, err := net.FileListener(os.NewFile(listenerFD, "listener"))
+	if err != nil {
+		return nil
+	}
+	return l
+}
+
+// RestoredQuestions returns the pending-question snapshot passed by a parent
+// process during a graceful restart, or nil on a normal start.
+func RestoredQuestions() []PendingQuestionSnapshot {
+	if os.Getenv(envGracefulRestart) != "1" {
+		return nil
+	}
+
+	file := os.NewFile(snapshotFD, "snapshot")
+	if file == nil {
+		return nil
+	}
+	defer file.Close()
+
+	var snapshot []PendingQuestionSnapshot
+	if err := json.NewDecoder(file).Decode(&snapshot); err != nil {
+		log.Printf("failed to decode restored question snapshot: %v", err)
+		return nil
+	}
+	return snapshot
+}
+
+// GracefulRestarter re-execs the running binary on SIGHUP, handing the
+// listening socket and a snapshot of in-flight questions to the new process
+// over inherited file descriptors so neither the accept queue nor pending
+// questions are dropped. The old process then drains: it stops accepting new
+// ask_human calls but leaves open backend dialogs running until they return,
+// then exits.
+type GracefulRestarter struct {
+	server   *AskHumanServer
+	listener net.Listener
+}
+
+// NewGracefulRestarter wires SIGHUP-triggered restarts for askServer, handing
+// off listener's underlying file descriptor to the re-exec'd process.
+func NewGracefulRestarter(askServer *AskHumanServer, listener net.Listener) *GracefulRestarter {
+	return &GracefulRestarter{server: askServer, listener: listener}
+}
+
+// Serve implements Service: it performs one restart per SIGHUP for as long
+// as ctx is alive.
+func (g *GracefulRestarter) Serve(ctx context.Context) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sigCh:
+			if err := g.restart(); err != nil {
+				log.Printf("graceful restart failed, continuing to serve: %v", err)
+			}
+		}
+	}
+}
+
+func (g *GracefulRestarter) restart() error {
+	type fileListener interface {
+		File() (*os.File, error)
+	}
+	fl, ok := g.listener.(fileListener)
+	if !ok {
+		return fmt.Errorf("listener type %T does not support fd inheritance", g.listener)
+	}
+	listenerFile, err := fl.File()
+	if err != nil {
+		return fmt.Errorf("failed to get listener file: %w", err)
+	}
+	defer listenerFile.Close()
+
+	snapshotRead, snapshotWrite, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot pipe: %w", err)
+	}
+	defer snapshotRead.Close()
+
+	go func() {
+		defer snapshotWrite.Close()
+		if err := json.NewEncoder(snapshotWrite).Encode(g.server.snapshotPendingQuestions()); err != nil {
+			log.Printf("failed to encode question snapshot for restart: %v", err)
+		}
+	}()
+
+	cmd := exec.Command(os.Args[0], os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = []*os.File{listenerFile, snapshotRead}
+	cmd.Env = append(os.Environ(), envGracefulRestart+"=1")
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start new instance: %w", err)
+	}
+
+	log.Printf("graceful restart: started new instance (pid %d); draining this one", cmd.Process.Pid)
+	go g.server.drain()
+	return nil
+}
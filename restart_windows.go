@@ -0,0 +1,45 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// PendingQuestionSnapshot mirrors the Unix definition so callers don't need
+// build tags of their own; Windows has no SIGHUP/fd-inheritance story so it
+// is never actually populated here.
+type PendingQuestionSnapshot struct {
+	ID        string    `json:"id"`
+	Question  string    `json:"question"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// ListenWithActivation always binds a fresh listener on Windows: there is no
+// LISTEN_FDS/socket-activation equivalent to check.
+func ListenWithActivation(host string, port int) (net.Listener, error) {
+	return net.Listen("tcp", fmt.Sprintf("%s:%d", host, port))
+}
+
+// RestoredQuestions always returns nil on Windows.
+func RestoredQuestions() []PendingQuestionSnapshot {
+	return nil
+}
+
+// GracefulRestarter is a no-op Service on Windows, which has no SIGHUP or
+// fork/exec fd-inheritance story to build a graceful restart on.
+type GracefulRestarter struct{}
+
+// NewGracefulRestarter returns a GracefulRestarter that never restarts.
+func NewGracefulRestarter(askServer *AskHumanServer, listener net.Listener) *GracefulRestarter {
+	return &GracefulRestarter{}
+}
+
+// Serve implements Service by blocking until ctx is done.
+func (g *GracefulRestarter) Serve(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
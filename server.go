@@ -2,27 +2,46 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net/http"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/masonyarbrough/ask-human-go/audit"
 )
 
-// Default interval for sending progress notifications to keep the connection alive
-const progressNotificationInterval = 30 * time.Second
+// pendingQuestionState tracks enough about an in-flight question to clean it
+// up on timeout and to survive a graceful restart. cancel is called by
+// cleanupTimeouts once deadline passes, with ErrQuestionTimedOut as the
+// cause, so the goroutine blocked waiting on the backend chain wakes up
+// with a distinguishable error instead of hanging until the client gives up.
+type pendingQuestionState struct {
+	question  string
+	startTime time.Time
+	deadline  time.Time
+	cancel    context.CancelCauseFunc
+}
 
-// AskHumanServer handles AI questions through zenity GUI dialogs
+// AskHumanServer handles AI questions through one or more pluggable human-input backends
 type AskHumanServer struct {
 	config           *Config
 	mcpServer        *server.MCPServer
-	zenityHandler    *ZenityHandler
-	pendingQuestions map[string]time.Time
+	backends         []AskBackend  // Tried in order; a failure falls back to the next
+	store            Store         // Persistent Q&A history; nil if config.StorePath is unset
+	auditLog         *audit.Logger // Per-call audit trail; nil if config.AuditLogPath is unset
+	supervisor       *Supervisor
+	pendingQuestions map[string]pendingQuestionState
 	mutex            sync.RWMutex
 	shutdownCtx      context.Context // Exported for HTTP mode shutdown coordination
-	shutdownCancel   context.CancelFunc
+	draining         atomic.Bool     // Set during a graceful restart; rejects new questions
 }
 
 // NewAskHumanServer creates a new Ask-Human MCP server
@@ -31,8 +50,6 @@ func NewAskHumanServer(config *Config) (*AskHumanServer, error) {
 		config = DefaultConfig()
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-
 	// Create MCP server
 	mcpServer := server.NewMCPServer(
 		"ask-human",
@@ -40,24 +57,103 @@ func NewAskHumanServer(config *Config) (*AskHumanServer, error) {
 		server.WithToolCapabilities(true),
 	)
 
+	supervisor := NewSupervisor(context.Background())
+
+	store, err := NewStore(config)
+	if err != nil {
+		supervisor.Close()
+		return nil, fmt.Errorf("failed to open Q&A store: %w", err)
+	}
+
+	backends, err := NewBackends(config)
+	if err != nil {
+		supervisor.Close()
+		return nil, fmt.Errorf("failed to configure ask backends: %w", err)
+	}
+
+	var auditLog *audit.Logger
+	if config.AuditLogPath != "" {
+		auditLog, err = audit.NewLogger(config.AuditLogPath)
+		if err != nil {
+			supervisor.Close()
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+	}
+
 	askServer := &AskHumanServer{
 		config:           config,
 		mcpServer:        mcpServer,
-		zenityHandler:    NewZenityHandler(config.Timeout),
-		pendingQuestions: make(map[string]time.Time),
-		shutdownCtx:      ctx,
-		shutdownCancel:   cancel,
+		backends:         backends,
+		store:            store,
+		auditLog:         auditLog,
+		supervisor:       supervisor,
+		pendingQuestions: make(map[string]pendingQuestionState),
+		shutdownCtx:      supervisor.Context(),
 	}
 
 	// Register MCP tools
 	askServer.registerTools()
 
-	// Start background cleanup goroutine
-	go askServer.cleanupLoop()
+	// Restore questions that were in flight across a graceful restart. No
+	// goroutine is waiting on these in this process, but cleanupTimeouts
+	// still needs a deadline and a cancel func to expire them.
+	for _, q := range RestoredQuestions() {
+		// The answer may have already landed in the store while the old
+		// process was mid-handoff; don't track it as pending in that case,
+		// since nothing in this process would ever deliver it and it would
+		// just occupy a slot until cleanupTimeouts expires it.
+		if store != nil {
+			if _, ok, err := store.FindAnswer(q.ID); err != nil {
+				log.Printf("failed to check store for restored question %s: %v", q.ID, err)
+			} else if ok {
+				log.Printf("restored question %s was already answered before handoff completed, dropping", q.ID)
+				continue
+			}
+		}
+
+		_, cancel := context.WithCancelCause(context.Background())
+		askServer.pendingQuestions[q.ID] = pendingQuestionState{
+			question:  q.Question,
+			startTime: q.StartTime,
+			deadline:  q.StartTime.Add(config.Timeout),
+			cancel:    cancel,
+		}
+	}
+
+	// Run subsystems under the supervisor instead of bare `go` statements so
+	// Close() can wait for them to actually finish and crashes get retried.
+	supervisor.Go("cleanup", &cleanupService{server: askServer})
+	go askServer.logSupervisorErrors()
 
 	return askServer, nil
 }
 
+// RegisterHTTPHandlers wires any backend-provided endpoints (the web
+// backend's /ask UI, the webhook backend's /answer/{id} callback) into mux.
+// Only relevant in HTTP mode.
+func (s *AskHumanServer) RegisterHTTPHandlers(mux *http.ServeMux) {
+	registerBackendHandlers(s.backends, mux)
+}
+
+// WatcherStats reports the file backend's notification delivery counters, if
+// a file backend is configured, for surfacing on the /health endpoint.
+func (s *AskHumanServer) WatcherStats() (WatcherStats, bool) {
+	for _, b := range s.backends {
+		if fb, ok := b.(*FileBackend); ok {
+			return fb.Stats(), true
+		}
+	}
+	return WatcherStats{}, false
+}
+
+// logSupervisorErrors surfaces supervised service failures that exhausted
+// their restart backoff instead of letting them vanish silently.
+func (s *AskHumanServer) logSupervisorErrors() {
+	for err := range s.supervisor.Errors() {
+		log.Printf("supervised service error: %v", err)
+	}
+}
+
 // registerTools registers the MCP tools that AI can call
 func (s *AskHumanServer) registerTools() {
 	askHumanTool := mcp.NewTool(
@@ -98,8 +194,18 @@ func (s *AskHumanServer) handleAskHuman(ctx context.Context, req mcp.CallToolReq
 	return mcp.NewToolResultText(answer), nil
 }
 
-// askQuestion handles the core question asking logic
+// askQuestion handles the core question asking logic, applying the global
+// config.Timeout as the question's deadline.
 func (s *AskHumanServer) askQuestion(ctx context.Context, question, contextInfo string, progressToken mcp.ProgressToken) (string, error) {
+	return s.AskWithDeadline(ctx, question, contextInfo, progressToken, time.Now().Add(s.config.Timeout))
+}
+
+// AskWithDeadline is askQuestion with an explicit per-question deadline,
+// for callers that need something shorter or longer than config.Timeout.
+// Once deadline passes, cleanupTimeouts cancels the question's context with
+// ErrQuestionTimedOut as the cause, so this returns that error instead of
+// leaving the caller blocked on a backend that never answers.
+func (s *AskHumanServer) AskWithDeadline(ctx context.Context, question, contextInfo string, progressToken mcp.ProgressToken, deadline time.Time) (string, error) {
 	// Validate inputs
 	if len(question) > s.config.MaxQuestionLength {
 		return "", fmt.Errorf("question too long: %d chars (max %d)", len(question), s.config.MaxQuestionLength)
@@ -117,32 +223,54 @@ func (s *AskHumanServer) askQuestion(ctx context.Context, question, contextInfo
 		return "", fmt.Errorf("too many pending questions: %d (max %d)", pendingCount, s.config.MaxPendingQuestions)
 	}
 
+	if s.draining.Load() {
+		return "", fmt.Errorf("server is draining for a graceful restart, try again shortly")
+	}
+
 	// Generate question ID
 	questionID := fmt.Sprintf("Q%s", uuid.New().String()[:8])
 
+	// qCtx is canceled either when ctx is (client disconnect, server
+	// shutdown) or, if neither happens first, by cleanupTimeouts once
+	// deadline passes.
+	qCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
 	// Track this question
+	now := time.Now()
 	s.mutex.Lock()
-	s.pendingQuestions[questionID] = time.Now()
+	s.pendingQuestions[questionID] = pendingQuestionState{question: question, startTime: now, deadline: deadline, cancel: cancel}
 	s.mutex.Unlock()
 
-	// Create a channel to receive the answer from zenity
-	type zenityResult struct {
-		answer string
-		err    error
+	if s.store != nil {
+		if err := s.store.AppendQuestion(questionID, question, contextInfo, now.Format(time.RFC3339)); err != nil {
+			log.Printf("failed to record question %s in store: %v", questionID, err)
+		}
+	}
+
+	// Create a channel to receive the answer from the backend chain
+	type backendResult struct {
+		answer  string
+		backend string
+		err     error
 	}
-	resultChan := make(chan zenityResult, 1)
+	resultChan := make(chan backendResult, 1)
 
-	// Start zenity dialog in a goroutine
 	go func() {
-		answer, err := s.zenityHandler.AskQuestion(ctx, questionID, question, contextInfo)
-		resultChan <- zenityResult{answer: answer, err: err}
+		answer, backend, err := s.askViaBackends(qCtx, questionID, question, contextInfo)
+		resultChan <- backendResult{answer: answer, backend: backend, err: err}
 	}()
 
-	// Start progress notification goroutine to keep the connection alive
-	progressCtx, cancelProgress := context.WithCancel(ctx)
+	// Run a progress notifier under the supervisor to keep the connection
+	// alive; it stops on its own once this question resolves.
+	progressCtx, cancelProgress := context.WithCancel(qCtx)
 	defer cancelProgress()
 
-	go s.sendProgressNotifications(progressCtx, progressToken, questionID)
+	s.supervisor.GoOnce(progressCtx, "progress:"+questionID, &progressNotifier{
+		progressToken: progressToken,
+		questionID:    questionID,
+		interval:      s.config.NotificationInterval,
+	})
 
 	// Wait for zenity result
 	result := <-resultChan
@@ -152,109 +280,145 @@ func (s *AskHumanServer) askQuestion(ctx context.Context, question, contextInfo
 	delete(s.pendingQuestions, questionID)
 	s.mutex.Unlock()
 
+	if s.store != nil && result.err == nil {
+		if err := s.store.RecordAnswer(questionID, result.answer); err != nil {
+			log.Printf("failed to record answer for %s in store: %v", questionID, err)
+		}
+	}
+
+	if s.auditLog != nil {
+		errMsg := ""
+		if result.err != nil {
+			errMsg = result.err.Error()
+		}
+		rec := audit.Record{
+			Timestamp:     now,
+			QuestionID:    questionID,
+			QuestionHash:  audit.HashQuestion(question),
+			ContextHash:   audit.HashQuestion(contextInfo),
+			Backend:       result.backend,
+			LatencyMS:     time.Since(now).Milliseconds(),
+			AnswerLength:  len(result.answer),
+			TimedOut:      errors.Is(result.err, ErrQuestionTimedOut) || errors.Is(result.err, context.DeadlineExceeded),
+			ClientSession: clientSessionID(ctx),
+			Error:         errMsg,
+		}
+		if err := s.auditLog.Log(rec); err != nil {
+			log.Printf("failed to write audit log entry for %s: %v", questionID, err)
+		}
+	}
+
 	return result.answer, result.err
 }
 
-// sendProgressNotifications sends periodic progress notifications to keep the MCP connection alive
-// This prevents the client from timing out while waiting for user input
-func (s *AskHumanServer) sendProgressNotifications(ctx context.Context, progressToken mcp.ProgressToken, questionID string) {
-	// Get client session from context to send notifications
+// clientSessionID returns the MCP client session identity associated with
+// ctx, or "" in stdio mode or when no session is attached (e.g. unit tests).
+func clientSessionID(ctx context.Context) string {
 	session := server.ClientSessionFromContext(ctx)
-	if session == nil || !session.Initialized() {
-		// No session available, cannot send progress notifications
-		return
+	if session == nil {
+		return ""
 	}
+	return session.SessionID()
+}
 
-	// If no progress token provided, generate one for internal use
-	// Note: The client may not process these if it didn't request progress,
-	// but sending them keeps the connection active
-	if progressToken == nil {
-		progressToken = questionID
+// askViaBackends tries each configured backend in order, falling back to the
+// next on failure (e.g. zenity erroring out on a headless box). It also
+// returns the name of the backend that produced the answer, for audit logs.
+func (s *AskHumanServer) askViaBackends(ctx context.Context, questionID, question, contextInfo string) (string, string, error) {
+	var lastErr error
+	for _, backend := range s.backends {
+		answer, err := backend.AskQuestion(ctx, questionID, question, contextInfo)
+		if err == nil {
+			return answer, backend.Name(), nil
+		}
+		if ctx.Err() != nil {
+			return "", "", context.Cause(ctx)
+		}
+		log.Printf("ask backend failed, trying next: %v", err)
+		lastErr = err
 	}
+	return "", "", lastErr
+}
 
-	ticker := time.NewTicker(progressNotificationInterval)
-	defer ticker.Stop()
-
-	startTime := time.Now()
-	notificationCount := 0
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			notificationCount++
-
-			// Calculate elapsed time for the progress message
-			elapsed := time.Since(startTime)
-			message := fmt.Sprintf("Waiting for human response... (%s elapsed)", elapsed.Round(time.Second))
-
-			// Create progress notification using the helper function
-			progressNotif := mcp.NewProgressNotification(progressToken, float64(notificationCount), nil, &message)
-
-			// Create JSONRPCNotification with the progress params
-			// We need to manually construct this because ProgressNotification has its own Params type
-			jsonrpcNotif := mcp.JSONRPCNotification{
-				JSONRPC: "2.0",
-				Notification: mcp.Notification{
-					Method: progressNotif.Notification.Method,
-					Params: mcp.NotificationParams{
-						AdditionalFields: map[string]any{
-							"progressToken": progressToken,
-							"progress":      float64(notificationCount),
-							"message":       message,
-						},
-					},
-				},
-			}
+// GetMCPServer returns the underlying MCP server
+func (s *AskHumanServer) GetMCPServer() *server.MCPServer {
+	return s.mcpServer
+}
 
-			// Send notification through session channel (non-blocking)
-			select {
-			case session.NotificationChannel() <- jsonrpcNotif:
-				// Notification sent successfully
-			default:
-				// Channel full or closed, stop sending notifications
-				return
-			}
-		}
+// snapshotPendingQuestions captures enough of the in-flight questions to
+// re-register them in a freshly exec'd process during a graceful restart.
+func (s *AskHumanServer) snapshotPendingQuestions() []PendingQuestionSnapshot {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	snapshot := make([]PendingQuestionSnapshot, 0, len(s.pendingQuestions))
+	for id, state := range s.pendingQuestions {
+		snapshot = append(snapshot, PendingQuestionSnapshot{
+			ID:        id,
+			Question:  state.question,
+			StartTime: state.startTime,
+		})
 	}
+	return snapshot
 }
 
-// cleanupLoop runs periodic cleanup of timed out questions
-func (s *AskHumanServer) cleanupLoop() {
-	ticker := time.NewTicker(s.config.CleanupInterval)
-	defer ticker.Stop()
-
+// drain stops this process from accepting new questions and blocks until
+// every question already in flight has been answered, then exits — the
+// other half of a graceful restart, once the new process has taken over.
+func (s *AskHumanServer) drain() {
+	s.draining.Store(true)
 	for {
-		select {
-		case <-s.shutdownCtx.Done():
-			return
-		case <-ticker.C:
-			s.cleanupTimeouts()
+		s.mutex.RLock()
+		n := len(s.pendingQuestions)
+		s.mutex.RUnlock()
+		if n == 0 {
+			break
 		}
+		time.Sleep(time.Second)
 	}
+	os.Exit(0)
 }
 
-// cleanupTimeouts removes questions that have timed out
-func (s *AskHumanServer) cleanupTimeouts() {
+// cancelPendingQuestions cancels every in-flight question's context with
+// cause, so a goroutine blocked in askViaBackends (or a progress notifier
+// tied to it via GoOnce) wakes up instead of running past shutdown. This
+// matters because those contexts are rooted in the originating request's
+// ctx, not the supervisor's: canceling the supervisor's root context alone
+// never reaches them.
+func (s *AskHumanServer) cancelPendingQuestions(cause error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	now := time.Now()
-	for questionID, startTime := range s.pendingQuestions {
-		if now.Sub(startTime) > s.config.Timeout {
-			delete(s.pendingQuestions, questionID)
+	for _, state := range s.pendingQuestions {
+		if state.cancel != nil {
+			state.cancel(cause)
 		}
 	}
 }
 
-// GetMCPServer returns the underlying MCP server
-func (s *AskHumanServer) GetMCPServer() *server.MCPServer {
-	return s.mcpServer
-}
-
-// Close shuts down the server and cleans up resources
+// Close shuts down the server and cleans up resources, waiting for every
+// supervised subsystem (cleanup, HTTP/SSE transports if running, any
+// in-flight progress notifiers) to actually return before returning itself.
 func (s *AskHumanServer) Close() error {
-	s.shutdownCancel()
-	return nil
+	// Unblock any in-flight question before supervisor.Close waits on its
+	// wg: those goroutines are registered via GoOnce but their contexts
+	// descend from the request ctx, so they won't learn the supervisor's
+	// root context was canceled on their own.
+	s.cancelPendingQuestions(ErrServerClosing)
+
+	err := s.supervisor.Close()
+	if s.store != nil {
+		if serr := s.store.Close(); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	if s.auditLog != nil {
+		if aerr := s.auditLog.Close(); aerr != nil && err == nil {
+			err = aerr
+		}
+	}
+	if berr := closeBackends(s.backends); berr != nil && err == nil {
+		err = berr
+	}
+	return err
 }
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// slackPollInterval is how often SlackBackend checks a thread for a reply.
+const slackPollInterval = 3 * time.Second
+
+// SlackBackend posts a question to a channel and waits for the first
+// threaded reply, using only Slack's plain HTTP Web API (no SDK dependency)
+// so it fits the rest of this codebase's style.
+type SlackBackend struct {
+	token   string
+	channel string
+	client  *http.Client
+}
+
+// NewSlackBackend builds a SlackBackend from cfg, which must set
+// SlackToken and SlackChannel.
+func NewSlackBackend(cfg *Config) (*SlackBackend, error) {
+	if cfg.SlackToken == "" || cfg.SlackChannel == "" {
+		return nil, fmt.Errorf("slack backend requires --slack-token and --slack-channel")
+	}
+	return &SlackBackend{
+		token:   cfg.SlackToken,
+		channel: cfg.SlackChannel,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name implements AskBackend.
+func (s *SlackBackend) Name() string { return "slack" }
+
+// AskQuestion implements AskBackend.
+func (s *SlackBackend) AskQuestion(ctx context.Context, questionID, question, contextInfo string) (string, error) {
+	text := fmt.Sprintf("*Question %s*\n%s", questionID, question)
+	if contextInfo != "" {
+		text += fmt.Sprintf("\n> %s", contextInfo)
+	}
+
+	threadTS, err := s.postMessage(ctx, text)
+	if err != nil {
+		return "", fmt.Errorf("failed to post question to slack: %w", err)
+	}
+
+	ticker := time.NewTicker(slackPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+			reply, ok, err := s.latestReply(ctx, threadTS)
+			if err != nil {
+				return "", fmt.Errorf("failed to poll slack thread: %w", err)
+			}
+			if ok {
+				return reply, nil
+			}
+		}
+	}
+}
+
+func (s *SlackBackend) postMessage(ctx context.Context, text string) (string, error) {
+	var resp struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		TS    string `json:"ts"`
+	}
+	if err := s.call(ctx, "chat.postMessage", map[string]string{
+		"channel": s.channel,
+		"text":    text,
+	}, &resp); err != nil {
+		return "", err
+	}
+	if !resp.OK {
+		return "", fmt.Errorf("slack API error: %s", resp.Error)
+	}
+	return resp.TS, nil
+}
+
+// latestReply returns the newest reply in the thread rooted at threadTS,
+// if one has arrived since the question was posted.
+func (s *SlackBackend) latestReply(ctx context.Context, threadTS string) (string, bool, error) {
+	var resp struct {
+		OK       bool   `json:"ok"`
+		Error    string `json:"error"`
+		Messages []struct {
+			TS   string `json:"ts"`
+			Text string `json:"text"`
+		} `json:"messages"`
+	}
+	if err := s.call(ctx, "conversations.replies", map[string]string{
+		"channel": s.channel,
+		"ts":      threadTS,
+	}, &resp); err != nil {
+		return "", false, err
+	}
+	if !resp.OK {
+		return "", false, fmt.Errorf("slack API error: %s", resp.Error)
+	}
+
+	// messages[0] is always the parent (the question itself); a reply has
+	// landed once there's anything after it.
+	if len(resp.Messages) < 2 {
+		return "", false, nil
+	}
+	last := resp.Messages[len(resp.Messages)-1]
+	return last.Text, true, nil
+}
+
+func (s *SlackBackend) call(ctx context.Context, method string, params map[string]string, out any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		"https://slack.com/api/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Store persists the question/answer history so that multiple server
+// instances (e.g. a stdio instance and an HTTP instance) pointed at the same
+// path can share it safely. AppendQuestion and FindAnswer preserve the
+// on-disk contract the markdown answers file has always used; RecordAnswer
+// lets backends that resolve an answer themselves (zenity, web UI, ...) close
+// out the history entry instead of waiting for an external edit.
+type Store interface {
+	// AppendQuestion records a newly asked question as pending.
+	AppendQuestion(questionID, question, context, timestamp string) error
+
+	// FindAnswer returns the recorded answer for questionID, if any. The
+	// second return value reports whether an answer (as opposed to a still-
+	// pending question) was found.
+	FindAnswer(questionID string) (string, bool, error)
+
+	// RecordAnswer fills in the answer for a previously appended question.
+	RecordAnswer(questionID, answer string) error
+
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// NewStore builds the Store configured by cfg. An empty StorePath disables
+// persistence entirely.
+func NewStore(cfg *Config) (Store, error) {
+	if cfg.StorePath == "" {
+		return nil, nil
+	}
+
+	backend := cfg.StoreBackend
+	if backend == "" || backend == "auto" {
+		if strings.HasSuffix(cfg.StorePath, ".db") || strings.HasSuffix(cfg.StorePath, ".sqlite") {
+			backend = "sqlite"
+		} else {
+			backend = "markdown"
+		}
+	}
+
+	switch backend {
+	case "markdown":
+		return NewMarkdownStore(cfg.StorePath), nil
+	case "sqlite":
+		return NewSQLiteStore(cfg.StorePath)
+	default:
+		return nil, fmt.Errorf("unknown store backend %q", backend)
+	}
+}
+
+// MarkdownStore persists Q&A history to the append-only markdown file format
+// that predates this interface, guarded by a FileLock so concurrent server
+// instances don't interleave writes.
+type MarkdownStore struct {
+	path string
+}
+
+// NewMarkdownStore creates a MarkdownStore backed by the file at path.
+func NewMarkdownStore(path string) *MarkdownStore {
+	return &MarkdownStore{path: path}
+}
+
+func (m *MarkdownStore) AppendQuestion(questionID, question, context, timestamp string) error {
+	return AppendQuestion(m.path, questionID, question, context, timestamp)
+}
+
+func (m *MarkdownStore) FindAnswer(questionID string) (string, bool, error) {
+	var content string
+	err := WithReadFileLock(m.path, func() error {
+		c, err := SafeReadText(m.path)
+		content = c
+		return err
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	answer, ok := FindAnswer(content, questionID)
+	return answer, ok, nil
+}
+
+func (m *MarkdownStore) RecordAnswer(questionID, answer string) error {
+	updated, err := UpdateAnswer(m.path, questionID, answer)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fmt.Errorf("%w: question %s", ErrAnswerNotFound, questionID)
+	}
+	return nil
+}
+
+func (m *MarkdownStore) Close() error {
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStore persists Q&A history in a SQLite database, giving concurrent
+// server instances the same shared-history guarantee as MarkdownStore without
+// needing an external FileLock — SQLite serializes writers itself.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) the SQLite database at path
+// and ensures its schema exists.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite store: %w", err)
+	}
+
+	// SQLite only supports one writer at a time; cap the pool so database/sql
+	// queues writers instead of erroring with "database is locked".
+	db.SetMaxOpenConns(1)
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS questions (
+	question_id TEXT PRIMARY KEY,
+	question    TEXT NOT NULL,
+	context     TEXT NOT NULL,
+	timestamp   TEXT NOT NULL,
+	answer      TEXT,
+	pending     INTEGER NOT NULL DEFAULT 1
+);`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize sqlite schema: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) AppendQuestion(questionID, question, context, timestamp string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO questions (question_id, question, context, timestamp, answer, pending)
+		 VALUES (?, ?, ?, ?, '', 1)`,
+		questionID, question, context, timestamp,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to append question: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) FindAnswer(questionID string) (string, bool, error) {
+	var answer string
+	var pending bool
+	err := s.db.QueryRow(
+		`SELECT answer, pending FROM questions WHERE question_id = ?`, questionID,
+	).Scan(&answer, &pending)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to query answer: %w", err)
+	}
+	return answer, !pending, nil
+}
+
+func (s *SQLiteStore) RecordAnswer(questionID, answer string) error {
+	res, err := s.db.Exec(
+		`UPDATE questions SET answer = ?, pending = 0 WHERE question_id = ?`,
+		answer, questionID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record answer: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: question %s", ErrAnswerNotFound, questionID)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Service is implemented by every subsystem the server's supervisor manages:
+// cleanup, the HTTP/SSE transports, and per-question progress notifiers.
+// Serve should block until ctx is done or it hits an unrecoverable error.
+type Service interface {
+	Serve(ctx context.Context) error
+}
+
+const (
+	restartBackoffInitial = 250 * time.Millisecond
+	restartBackoffMax     = 30 * time.Second
+)
+
+// Supervisor runs Services under a shared root context, restarting any that
+// return an error with exponential backoff, and makes shutdown deterministic:
+// Close cancels the root context and blocks until every service it started
+// has actually returned.
+type Supervisor struct {
+	rootCtx context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	errCh   chan error
+}
+
+// NewSupervisor creates a Supervisor whose services are cancelled via a
+// context derived from parent.
+func NewSupervisor(parent context.Context) *Supervisor {
+	ctx, cancel := context.WithCancel(parent)
+	return &Supervisor{
+		rootCtx: ctx,
+		cancel:  cancel,
+		errCh:   make(chan error, 8),
+	}
+}
+
+// Context returns the supervisor's root context, cancelled when Close runs.
+func (sv *Supervisor) Context() context.Context {
+	return sv.rootCtx
+}
+
+// Go starts svc under supervision, restarting it with exponential backoff
+// each time it returns a non-nil error, until the root context is cancelled.
+func (sv *Supervisor) Go(name string, svc Service) {
+	sv.wg.Add(1)
+	go sv.run(name, svc)
+}
+
+// GoOnce starts svc exactly once against ctx (no restart), for short-lived
+// per-request helpers that should stop on their own schedule rather than the
+// supervisor's. Close still waits for it, so shutdown stays deterministic.
+func (sv *Supervisor) GoOnce(ctx context.Context, name string, svc Service) {
+	sv.wg.Add(1)
+	go func() {
+		defer sv.wg.Done()
+		if err := svc.Serve(ctx); err != nil && sv.rootCtx.Err() == nil && ctx.Err() == nil {
+			sv.publish(name, err)
+		}
+	}()
+}
+
+func (sv *Supervisor) run(name string, svc Service) {
+	defer sv.wg.Done()
+
+	backoff := restartBackoffInitial
+	for {
+		err := svc.Serve(sv.rootCtx)
+		if sv.rootCtx.Err() != nil {
+			return
+		}
+		if err == nil {
+			return
+		}
+
+		sv.publish(name, err)
+		log.Printf("service %s exited with error, restarting in %s: %v", name, backoff, err)
+
+		select {
+		case <-sv.rootCtx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > restartBackoffMax {
+			backoff = restartBackoffMax
+		}
+	}
+}
+
+func (sv *Supervisor) publish(name string, err error) {
+	select {
+	case sv.errCh <- fmt.Errorf("%s: %w", name, err):
+	default:
+		// Error channel full; the restart log line above is the backstop.
+	}
+}
+
+// Errors returns the channel aggregated service failures are published on.
+// It is closed once Close has finished waiting for all services to return.
+func (sv *Supervisor) Errors() <-chan error {
+	return sv.errCh
+}
+
+// Close cancels the root context and waits for every supervised service to
+// return before returning itself.
+func (sv *Supervisor) Close() error {
+	sv.cancel()
+	sv.wg.Wait()
+	close(sv.errCh)
+	return nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Answer is one parsed record out of the answers file: the question it
+// answers and the answer text itself.
+type Answer struct {
+	QuestionID string
+	Text       string
+}
+
+// TailReader follows the answers file the way `tail -f` does, instead of
+// re-reading and re-parsing the whole file on every change: it keeps an
+// open handle and a byte offset, and on each OnChange call only reads and
+// parses what's been appended since the last call, dispatching parsed
+// records by QuestionID to the channel RegisterCallback returned. This
+// turns notification handling from O(file size) into O(bytes appended),
+// which matters once the answers file has accumulated a long history.
+type TailReader struct {
+	path  string
+	parse func([]byte) ([]Answer, int, error)
+
+	mu     sync.Mutex
+	file   *os.File
+	info   os.FileInfo // last known FileInfo, used to detect rotation via os.SameFile
+	offset int64
+	buf    []byte // unparsed bytes carried over, e.g. a partial trailing record
+
+	callbackMu sync.RWMutex
+	callbacks  map[string]chan Answer
+}
+
+// NewTailReader creates a TailReader for the answers file at path. parse
+// extracts as many complete records as it can find from the front of its
+// input and returns them along with how many bytes they consumed; any
+// unconsumed tail (a partial record) is carried over to the next OnChange.
+func NewTailReader(path string, parse func([]byte) ([]Answer, int, error)) *TailReader {
+	return &TailReader{
+		path:      path,
+		parse:     parse,
+		callbacks: make(map[string]chan Answer),
+	}
+}
+
+// RegisterCallback returns a channel that receives an Answer each time
+// OnChange parses one for questionID, until UnregisterCallback closes it.
+func (t *TailReader) RegisterCallback(questionID string) <-chan Answer {
+	t.callbackMu.Lock()
+	defer t.callbackMu.Unlock()
+
+	ch := make(chan Answer, 1)
+	t.callbacks[questionID] = ch
+	return ch
+}
+
+// UnregisterCallback closes and removes questionID's channel.
+func (t *TailReader) UnregisterCallback(questionID string) {
+	t.callbackMu.Lock()
+	defer t.callbackMu.Unlock()
+
+	if ch, ok := t.callbacks[questionID]; ok {
+		close(ch)
+		delete(t.callbacks, questionID)
+	}
+}
+
+// OnChange reads and dispatches whatever has been appended to the answers
+// file since the last call. Call it whenever a Watcher reports the file
+// changed. It reopens the file when the inode has changed (rotation, or an
+// atomic-rename save replacing the file) and seeks to 0 when the file has
+// shrunk (truncation), so a reader never gets stuck past the current EOF.
+func (t *TailReader) OnChange() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	info, err := os.Stat(t.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // nothing to tail yet
+		}
+		return fmt.Errorf("stat answers file: %w", err)
+	}
+
+	if t.file == nil || t.info == nil || !os.SameFile(t.info, info) {
+		if t.file != nil {
+			t.file.Close()
+		}
+		f, err := os.Open(t.path)
+		if err != nil {
+			return fmt.Errorf("open answers file: %w", err)
+		}
+		t.file = f
+		t.offset = 0
+		t.buf = nil
+	}
+	t.info = info
+
+	if info.Size() < t.offset {
+		t.offset = 0
+		t.buf = nil
+	}
+
+	if info.Size() == t.offset {
+		return nil
+	}
+
+	if _, err := t.file.Seek(t.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seek answers file: %w", err)
+	}
+
+	fresh := make([]byte, info.Size()-t.offset)
+	n, err := io.ReadFull(t.file, fresh)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("read answers file: %w", err)
+	}
+	t.offset += int64(n)
+	t.buf = append(t.buf, fresh[:n]...)
+
+	answers, consumed, err := t.parse(t.buf)
+	if err != nil {
+		return fmt.Errorf("parse answers file: %w", err)
+	}
+	if consumed > 0 {
+		t.buf = t.buf[consumed:]
+	}
+	t.dispatch(answers)
+
+	return nil
+}
+
+// dispatch delivers each answer to its questionID's registered channel, if
+// any, dropping it if the channel already has an undelivered value.
+func (t *TailReader) dispatch(answers []Answer) {
+	if len(answers) == 0 {
+		return
+	}
+
+	t.callbackMu.RLock()
+	defer t.callbackMu.RUnlock()
+
+	for _, a := range answers {
+		if ch, ok := t.callbacks[a.QuestionID]; ok {
+			select {
+			case ch <- a:
+			default:
+			}
+		}
+	}
+}
+
+// Reset forces the next OnChange to re-read the file from the beginning
+// instead of from the last offset, e.g. after a Watcher reports that one or
+// more change notifications were coalesced and the offset can no longer be
+// trusted to have seen every intermediate write.
+func (t *TailReader) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.offset = 0
+	t.buf = nil
+}
+
+// Close releases the underlying file handle.
+func (t *TailReader) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file != nil {
+		return t.file.Close()
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TerminalBackend asks the question on stdin/stdout, for headless servers
+// and Docker images with no GUI. It only makes sense in HTTP mode: in stdio
+// mode stdin is already the MCP transport.
+type TerminalBackend struct {
+	reader *bufio.Reader
+}
+
+// NewTerminalBackend creates a TerminalBackend reading from os.Stdin.
+func NewTerminalBackend() *TerminalBackend {
+	return &TerminalBackend{reader: bufio.NewReader(os.Stdin)}
+}
+
+// Name implements AskBackend.
+func (t *TerminalBackend) Name() string { return "terminal" }
+
+// AskQuestion implements AskBackend.
+func (t *TerminalBackend) AskQuestion(ctx context.Context, questionID, question, contextInfo string) (string, error) {
+	fmt.Fprintf(os.Stdout, "\n[%s] %s\n", questionID, question)
+	if contextInfo != "" {
+		fmt.Fprintf(os.Stdout, "Context: %s\n", contextInfo)
+	}
+	fmt.Fprint(os.Stdout, "Your answer: ")
+
+	type readResult struct {
+		line string
+		err  error
+	}
+	resultCh := make(chan readResult, 1)
+	go func() {
+		line, err := t.reader.ReadString('\n')
+		resultCh <- readResult{line: line, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case r := <-resultCh:
+		if r.err != nil {
+			return "", fmt.Errorf("failed to read terminal answer: %w", r.err)
+		}
+		answer := strings.TrimSpace(r.line)
+		if answer == "" {
+			return "", fmt.Errorf("empty answer provided")
+		}
+		return answer, nil
+	}
+}
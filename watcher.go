@@ -1,122 +1,100 @@
 package main
 
 import (
-	"context"
-	"log"
+	"fmt"
+	"os"
 	"path/filepath"
-	"sync"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 )
 
-// FileWatcher monitors file changes and notifies waiting callbacks
-type FileWatcher struct {
-	filePath  string
-	watcher   *fsnotify.Watcher
-	callbacks map[string]chan struct{}
-	mutex     sync.RWMutex
-	ctx       context.Context
-	cancel    context.CancelFunc
+// Watcher notifies registered callbacks when the target answers file
+// changes. FSNotifyWatcher backs it with native OS filesystem events;
+// PollWatcher stats the file on an interval for mounts where those events
+// don't reliably arrive (NFS, FUSE, container bind mounts, network shares).
+type Watcher interface {
+	// RegisterCallback returns a channel that receives a ChangeEvent
+	// whenever the watched file changes, until UnregisterCallback closes it.
+	RegisterCallback(questionID string) <-chan ChangeEvent
+	// UnregisterCallback closes and removes questionID's channel.
+	UnregisterCallback(questionID string)
+	// NotifyAll signals every registered callback that the file changed.
+	NotifyAll()
+	// Stats reports this watcher's notification delivery counters.
+	Stats() WatcherStats
+	Close() error
 }
 
-// NewFileWatcher creates a new file watcher for the specified file
-func NewFileWatcher(filePath string) (*FileWatcher, error) {
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		return nil, err
+// probeTimeout bounds how long NewWatcher waits for a test write to the
+// parent directory to surface as an fsnotify event during WatcherMode "auto".
+const probeTimeout = 500 * time.Millisecond
+
+// NewWatcher builds a Watcher for filePath according to cfg.WatcherMode:
+// "fsnotify" forces native OS events, "poll" forces stat-based polling, and
+// "auto" (the default) probes for working fsnotify support and falls back
+// to polling if it's unreliable.
+func NewWatcher(filePath string, cfg *Config) (Watcher, error) {
+	switch cfg.WatcherMode {
+	case "fsnotify":
+		return NewFSNotifyWatcher(filePath)
+	case "poll":
+		return NewPollWatcher(filePath, cfg.WatcherPollInterval), nil
+	case "auto", "":
+		if fsnotifyWorks(filePath) {
+			if w, err := NewFSNotifyWatcher(filePath); err == nil {
+				return w, nil
+			}
+		}
+		return NewPollWatcher(filePath, cfg.WatcherPollInterval), nil
+	default:
+		return nil, fmt.Errorf("unknown watcher mode %q", cfg.WatcherMode)
 	}
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
+// fsnotifyWorks probes whether fsnotify actually delivers events for
+// filePath's parent directory: it watches the directory, writes a
+// throwaway temp file into it, and checks that an event for that file
+// arrives within probeTimeout. Some NFS mounts, FUSE filesystems, and
+// container bind mounts accept the watch but never deliver the event,
+// which this catches before NewWatcher commits to fsnotify.
+func fsnotifyWorks(filePath string) bool {
+	dir := filepath.Dir(filePath)
 
-	fw := &FileWatcher{
-		filePath:  filePath,
-		watcher:   watcher,
-		callbacks: make(map[string]chan struct{}),
-		ctx:       ctx,
-		cancel:    cancel,
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return false
 	}
+	defer watcher.Close()
 
-	// Watch the parent directory since the file might not exist yet
-	dir := filepath.Dir(filePath)
 	if err := watcher.Add(dir); err != nil {
-		watcher.Close()
-		cancel()
-		return nil, err
+		return false
 	}
 
-	go fw.watchLoop()
-
-	return fw, nil
-}
-
-// RegisterCallback registers a callback for a specific question ID
-func (fw *FileWatcher) RegisterCallback(questionID string) <-chan struct{} {
-	fw.mutex.Lock()
-	defer fw.mutex.Unlock()
-
-	ch := make(chan struct{}, 1)
-	fw.callbacks[questionID] = ch
-	return ch
-}
-
-// UnregisterCallback removes a callback for a question ID
-func (fw *FileWatcher) UnregisterCallback(questionID string) {
-	fw.mutex.Lock()
-	defer fw.mutex.Unlock()
-
-	if ch, exists := fw.callbacks[questionID]; exists {
-		close(ch)
-		delete(fw.callbacks, questionID)
-	}
-}
-
-// NotifyAll notifies all registered callbacks
-func (fw *FileWatcher) NotifyAll() {
-	fw.mutex.RLock()
-	defer fw.mutex.RUnlock()
-
-	for _, ch := range fw.callbacks {
-		select {
-		case ch <- struct{}{}:
-		default:
-			// Channel is full, skip notification
-		}
+	probe, err := os.CreateTemp(dir, ".ask-human-watch-probe-*")
+	if err != nil {
+		return false
 	}
-}
+	probePath := probe.Name()
+	probe.Close()
+	defer os.Remove(probePath)
 
-// watchLoop runs the file watching loop
-func (fw *FileWatcher) watchLoop() {
-	defer fw.watcher.Close()
+	timeout := time.NewTimer(probeTimeout)
+	defer timeout.Stop()
 
 	for {
 		select {
-		case <-fw.ctx.Done():
-			return
-
-		case event, ok := <-fw.watcher.Events:
+		case event, ok := <-watcher.Events:
 			if !ok {
-				return
+				return false
 			}
-
-			// Check if the event is for our target file
-			if event.Name == fw.filePath {
-				if event.Op&fsnotify.Write == fsnotify.Write ||
-					event.Op&fsnotify.Create == fsnotify.Create {
-					fw.NotifyAll()
-				}
+			if event.Name == probePath {
+				return true
 			}
-
-		case err, ok := <-fw.watcher.Errors:
-			if !ok {
-				return
-			}
-			log.Printf("File watcher error: %v", err)
+		case <-watcher.Errors:
+			return false
+		case <-timeout.C:
+			return false
 		}
 	}
 }
-
-// Close stops the file watcher
-func (fw *FileWatcher) Close() error {
-	fw.cancel()
-	return fw.watcher.Close()
-}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// defaultDebounceInterval is how long FSNotifyWatcher waits for quiet
+// before turning a burst of raw fsnotify events into one NotifyAll call.
+const defaultDebounceInterval = 100 * time.Millisecond
+
+// FSNotifyWatcher implements Watcher using the OS's native filesystem event
+// API (inotify, kqueue, ReadDirectoryChangesW), via fsnotify.
+type FSNotifyWatcher struct {
+	*changeNotifier
+
+	filePath string
+	watcher  *fsnotify.Watcher
+	ctx      context.Context
+	cancel   context.CancelFunc
+
+	debounceInterval time.Duration
+	rawChanges       chan struct{} // buffered 1; watchLoop signals, debounceLoop coalesces
+}
+
+// NewFSNotifyWatcher creates a Watcher for filePath backed by fsnotify,
+// coalescing bursts of raw events within defaultDebounceInterval into a
+// single notification.
+func NewFSNotifyWatcher(filePath string) (*FSNotifyWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	fw := &FSNotifyWatcher{
+		changeNotifier:   newChangeNotifier(),
+		filePath:         filePath,
+		watcher:          watcher,
+		ctx:              ctx,
+		cancel:           cancel,
+		debounceInterval: defaultDebounceInterval,
+		rawChanges:       make(chan struct{}, 1),
+	}
+
+	// Watch the parent directory since the file might not exist yet
+	dir := filepath.Dir(filePath)
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		cancel()
+		return nil, err
+	}
+
+	go fw.watchLoop()
+	go fw.debounceLoop()
+
+	return fw, nil
+}
+
+// watchLoop runs the file watching loop. It forwards raw Write/Create
+// events for filePath to debounceLoop for coalescing, and on Remove/Rename
+// re-adds the parent directory so a subsequent Create (e.g. an editor's
+// atomic-rename save) still surfaces as a change.
+func (fw *FSNotifyWatcher) watchLoop() {
+	defer fw.watcher.Close()
+
+	for {
+		select {
+		case <-fw.ctx.Done():
+			return
+
+		case event, ok := <-fw.watcher.Events:
+			if !ok {
+				return
+			}
+
+			if event.Name != fw.filePath {
+				continue
+			}
+
+			switch {
+			case event.Op&(fsnotify.Write|fsnotify.Create) != 0:
+				fw.signalChange()
+			case event.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+				fw.reestablishWatch()
+			}
+
+		case err, ok := <-fw.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("File watcher error: %v", err)
+		}
+	}
+}
+
+// signalChange enqueues a raw-change signal for debounceLoop, coalescing
+// with any signal already pending.
+func (fw *FSNotifyWatcher) signalChange() {
+	select {
+	case fw.rawChanges <- struct{}{}:
+	default:
+	}
+}
+
+// reestablishWatch re-adds filePath's parent directory after a Remove or
+// Rename detaches it, e.g. when filePath's parent is itself recreated or
+// the watch is otherwise dropped by the OS.
+func (fw *FSNotifyWatcher) reestablishWatch() {
+	dir := filepath.Dir(fw.filePath)
+	if err := fw.watcher.Add(dir); err != nil {
+		log.Printf("File watcher: failed to re-add watch on %s: %v", dir, err)
+	}
+}
+
+// debounceLoop coalesces bursts of rawChanges into a single NotifyAll once
+// debounceInterval has passed with no further signal, so each logical
+// answer write wakes callers exactly once regardless of how many raw
+// events the OS emitted for it.
+func (fw *FSNotifyWatcher) debounceLoop() {
+	var timer *time.Timer
+	var timerC <-chan time.Time
+
+	for {
+		select {
+		case <-fw.ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+
+		case <-fw.rawChanges:
+			if timer == nil {
+				timer = time.NewTimer(fw.debounceInterval)
+			} else {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(fw.debounceInterval)
+			}
+			timerC = timer.C
+
+		case <-timerC:
+			fw.NotifyAll()
+			timerC = nil
+		}
+	}
+}
+
+// Close implements Watcher.
+func (fw *FSNotifyWatcher) Close() error {
+	fw.cancel()
+	return fw.watcher.Close()
+}
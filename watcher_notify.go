@@ -0,0 +1,104 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ChangeEvent is delivered to a callback channel each time the watched file
+// changes. Overflowed is set when one or more prior changes for this
+// questionID were coalesced because the channel was full; the consumer
+// should treat it as a cue to bypass incremental optimizations (like
+// TailReader's offset tracking) and re-scan the whole answers file, since it
+// can no longer assume it saw every intermediate state.
+type ChangeEvent struct {
+	Overflowed bool
+}
+
+// WatcherStats reports how a Watcher's notification delivery has behaved,
+// for tuning the debounce window and channel buffer size to a workload.
+type WatcherStats struct {
+	Delivered  uint64 // events sent to a callback channel
+	Coalesced  uint64 // events dropped because the channel was full
+	Overflowed uint64 // events sent with Overflowed set, telling the consumer to re-scan
+}
+
+// changeNotifier is the callback registry and delivery logic shared by every
+// Watcher implementation, borrowing fsnotify's own ErrEventOverflow
+// semantics: each callback gets a bounded channel, and a send that would
+// block instead marks that callback overflowed so the next successful send
+// tells the consumer to distrust anything it inferred since.
+type changeNotifier struct {
+	mutex     sync.RWMutex
+	callbacks map[string]*notifyEntry
+
+	delivered  uint64
+	coalesced  uint64
+	overflowed uint64
+}
+
+type notifyEntry struct {
+	ch         chan ChangeEvent
+	overflowed atomic.Bool
+}
+
+func newChangeNotifier() *changeNotifier {
+	return &changeNotifier{callbacks: make(map[string]*notifyEntry)}
+}
+
+// RegisterCallback returns a channel that receives a ChangeEvent whenever
+// NotifyAll runs, until UnregisterCallback closes it.
+func (n *changeNotifier) RegisterCallback(questionID string) <-chan ChangeEvent {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	entry := &notifyEntry{ch: make(chan ChangeEvent, 1)}
+	n.callbacks[questionID] = entry
+	return entry.ch
+}
+
+// UnregisterCallback closes and removes questionID's channel.
+func (n *changeNotifier) UnregisterCallback(questionID string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if entry, exists := n.callbacks[questionID]; exists {
+		close(entry.ch)
+		delete(n.callbacks, questionID)
+	}
+}
+
+// NotifyAll signals every registered callback that the file changed. A
+// callback whose channel is still full from a previous notification is
+// marked overflowed instead of blocking or being silently dropped forever:
+// the next time there's room, that callback receives a ChangeEvent with
+// Overflowed set rather than a normal one.
+func (n *changeNotifier) NotifyAll() {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	for _, entry := range n.callbacks {
+		ev := ChangeEvent{Overflowed: entry.overflowed.Load()}
+
+		select {
+		case entry.ch <- ev:
+			atomic.AddUint64(&n.delivered, 1)
+			if ev.Overflowed {
+				entry.overflowed.Store(false)
+				atomic.AddUint64(&n.overflowed, 1)
+			}
+		default:
+			entry.overflowed.Store(true)
+			atomic.AddUint64(&n.coalesced, 1)
+		}
+	}
+}
+
+// Stats reports this notifier's delivery counters.
+func (n *changeNotifier) Stats() WatcherStats {
+	return WatcherStats{
+		Delivered:  atomic.LoadUint64(&n.delivered),
+		Coalesced:  atomic.LoadUint64(&n.coalesced),
+		Overflowed: atomic.LoadUint64(&n.overflowed),
+	}
+}
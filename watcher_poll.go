@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"os"
+	"time"
+)
+
+// PollWatcher implements Watcher by stat'ing (and, for small files,
+// hashing) the target file on a fixed interval. It's the fallback for
+// mounts where fsnotify's native OS events don't reliably arrive, e.g.
+// NFS, some FUSE filesystems, container bind mounts, and network shares.
+type PollWatcher struct {
+	*changeNotifier
+
+	filePath string
+	interval time.Duration
+	ctx      context.Context
+	cancel   context.CancelFunc
+
+	lastSize  int64
+	lastMtime time.Time
+	lastHash  [32]byte
+}
+
+// NewPollWatcher creates a Watcher for filePath that polls every interval.
+// A non-positive interval falls back to a 200ms default.
+func NewPollWatcher(filePath string, interval time.Duration) *PollWatcher {
+	if interval <= 0 {
+		interval = 200 * time.Millisecond
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	pw := &PollWatcher{
+		changeNotifier: newChangeNotifier(),
+		filePath:       filePath,
+		interval:       interval,
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+	pw.lastSize, pw.lastMtime, pw.lastHash = pw.snapshot()
+
+	go pw.pollLoop()
+
+	return pw
+}
+
+// pollLoop stats filePath every interval and fires NotifyAll when size,
+// mtime, or content hash has changed since the last poll.
+func (pw *PollWatcher) pollLoop() {
+	ticker := time.NewTicker(pw.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pw.ctx.Done():
+			return
+		case <-ticker.C:
+			size, mtime, hash := pw.snapshot()
+			if size != pw.lastSize || !mtime.Equal(pw.lastMtime) || hash != pw.lastHash {
+				pw.lastSize, pw.lastMtime, pw.lastHash = size, mtime, hash
+				pw.NotifyAll()
+			}
+		}
+	}
+}
+
+// snapshot reads filePath's current size, mtime, and content hash. A
+// missing or unreadable file reports as the zero value, which is treated
+// as "changed" the moment the file appears.
+func (pw *PollWatcher) snapshot() (int64, time.Time, [32]byte) {
+	info, err := os.Stat(pw.filePath)
+	if err != nil {
+		return 0, time.Time{}, [32]byte{}
+	}
+
+	f, err := os.Open(pw.filePath)
+	if err != nil {
+		return info.Size(), info.ModTime(), [32]byte{}
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return info.Size(), info.ModTime(), [32]byte{}
+	}
+
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+	return info.Size(), info.ModTime(), sum
+}
+
+// Close implements Watcher.
+func (pw *PollWatcher) Close() error {
+	pw.cancel()
+	return nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// webLongPollTimeout bounds how long a GET /ask/next request waits for a
+// question before returning 204 so the browser can poll again.
+const webLongPollTimeout = 25 * time.Second
+
+// WebBackend serves a small built-in UI on the same HTTP mux used for MCP
+// traffic. AskQuestion publishes a pending question; a human with /ask open
+// long-polls GET /ask/next for it and replies with POST /ask/answer/{id}.
+type WebBackend struct {
+	mu      sync.Mutex
+	pending map[string]webPendingQuestion
+	waiters chan struct{} // closed and replaced whenever a new question arrives
+}
+
+type webPendingQuestion struct {
+	question string
+	context  string
+	answerCh chan string
+}
+
+// NewWebBackend creates an empty WebBackend; call RegisterHandlers to expose it.
+func NewWebBackend() *WebBackend {
+	return &WebBackend{
+		pending: make(map[string]webPendingQuestion),
+		waiters: make(chan struct{}),
+	}
+}
+
+// Name implements AskBackend.
+func (w *WebBackend) Name() string { return "web" }
+
+// AskQuestion implements AskBackend.
+func (w *WebBackend) AskQuestion(ctx context.Context, questionID, question, contextInfo string) (string, error) {
+	answerCh := make(chan string, 1)
+
+	w.mu.Lock()
+	w.pending[questionID] = webPendingQuestion{question: question, context: contextInfo, answerCh: answerCh}
+	close(w.waiters)
+	w.waiters = make(chan struct{})
+	w.mu.Unlock()
+
+	defer func() {
+		w.mu.Lock()
+		delete(w.pending, questionID)
+		w.mu.Unlock()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case answer := <-answerCh:
+		return answer, nil
+	}
+}
+
+// RegisterHandlers implements httpRegistrar, wiring the page, the long-poll
+// endpoint, and the answer-submission endpoint into mux.
+func (w *WebBackend) RegisterHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("/ask", w.handlePage)
+	mux.HandleFunc("/ask/next", w.handleNext)
+	mux.HandleFunc("/ask/answer/", w.handleAnswer)
+}
+
+func (w *WebBackend) handlePage(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	rw.Write([]byte(webBackendPageHTML))
+}
+
+// handleNext long-polls for the next pending question, in arbitrary map
+// order (there is normally at most a handful pending at once).
+func (w *WebBackend) handleNext(rw http.ResponseWriter, r *http.Request) {
+	deadline := time.After(webLongPollTimeout)
+
+	for {
+		w.mu.Lock()
+		for id, q := range w.pending {
+			w.mu.Unlock()
+			rw.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(rw).Encode(map[string]string{
+				"id":       id,
+				"question": q.question,
+				"context":  q.context,
+			})
+			return
+		}
+		waiters := w.waiters
+		w.mu.Unlock()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-waiters:
+		case <-deadline:
+			rw.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+}
+
+func (w *WebBackend) handleAnswer(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/ask/answer/")
+
+	var body struct {
+		Answer string `json:"answer"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(rw, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	w.mu.Lock()
+	q, ok := w.pending[id]
+	w.mu.Unlock()
+	if !ok {
+		http.Error(rw, "unknown or already-answered question", http.StatusNotFound)
+		return
+	}
+
+	select {
+	case q.answerCh <- body.Answer:
+	default:
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+const webBackendPageHTML = `<!doctype html>
+<html>
+<head><meta charset="utf-8"><title>Ask-Human</title></head>
+<body>
+<h1>Ask-Human</h1>
+<div id="question">Waiting for a question...</div>
+<form id="answer-form" style="display:none">
+  <textarea id="answer" rows="4" cols="60"></textarea><br>
+  <button type="submit">Submit</button>
+</form>
+<script>
+let currentID = null;
+
+async function poll() {
+  if (currentID) {
+    // A question is already on screen; resume polling once it's submitted
+    // instead of long-polling /ask/next again for the same pending question.
+    return;
+  }
+  try {
+    const res = await fetch('/ask/next');
+    if (res.status === 200) {
+      const q = await res.json();
+      currentID = q.id;
+      document.getElementById('question').textContent =
+        q.question + (q.context ? ' (' + q.context + ')' : '');
+      document.getElementById('answer-form').style.display = 'block';
+      return;
+    }
+  } catch (e) {
+    // transient network error; keep polling
+  }
+  poll();
+}
+
+document.getElementById('answer-form').addEventListener('submit', async (e) => {
+  e.preventDefault();
+  if (!currentID) return;
+  const answer = document.getElementById('answer').value;
+  await fetch('/ask/answer/' + currentID, {
+    method: 'POST',
+    headers: {'Content-Type': 'application/json'},
+    body: JSON.stringify({answer: answer}),
+  });
+  document.getElementById('answer').value = '';
+  document.getElementById('answer-form').style.display = 'none';
+  document.getElementById('question').textContent = 'Waiting for a question...';
+  currentID = null;
+  poll();
+});
+
+poll();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebhookBackend POSTs the question to an external URL and waits for that
+// system to call back to /answer/{id} with the answer, for teams that want
+// to wire ask-human into their own ticketing or chat tooling.
+type WebhookBackend struct {
+	url          string
+	callbackBase string
+	client       *http.Client
+
+	mu      sync.Mutex
+	pending map[string]chan string
+}
+
+// NewWebhookBackend builds a WebhookBackend from cfg, which must set
+// WebhookURL and WebhookCallbackBase (this server's externally reachable
+// base URL, e.g. "http://10.0.0.5:3000").
+func NewWebhookBackend(cfg *Config) (*WebhookBackend, error) {
+	if cfg.WebhookURL == "" {
+		return nil, fmt.Errorf("webhook backend requires --webhook-url")
+	}
+	if cfg.WebhookCallbackBase == "" {
+		return nil, fmt.Errorf("webhook backend requires --webhook-callback-base")
+	}
+	return &WebhookBackend{
+		url:          cfg.WebhookURL,
+		callbackBase: strings.TrimSuffix(cfg.WebhookCallbackBase, "/"),
+		client:       &http.Client{Timeout: 10 * time.Second},
+		pending:      make(map[string]chan string),
+	}, nil
+}
+
+// Name implements AskBackend.
+func (w *WebhookBackend) Name() string { return "webhook" }
+
+// AskQuestion implements AskBackend.
+func (w *WebhookBackend) AskQuestion(ctx context.Context, questionID, question, contextInfo string) (string, error) {
+	answerCh := make(chan string, 1)
+	w.mu.Lock()
+	w.pending[questionID] = answerCh
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		delete(w.pending, questionID)
+		w.mu.Unlock()
+	}()
+
+	payload, err := json.Marshal(map[string]string{
+		"id":           questionID,
+		"question":     question,
+		"context":      contextInfo,
+		"callback_url": fmt.Sprintf("%s/answer/%s", w.callbackBase, questionID),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case answer := <-answerCh:
+		return answer, nil
+	}
+}
+
+// RegisterHandlers implements httpRegistrar, exposing the /answer/{id}
+// callback the webhook's caller is expected to POST to.
+func (w *WebhookBackend) RegisterHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("/answer/", w.handleAnswer)
+}
+
+func (w *WebhookBackend) handleAnswer(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/answer/")
+
+	var body struct {
+		Answer string `json:"answer"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(rw, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	w.mu.Lock()
+	answerCh, ok := w.pending[id]
+	w.mu.Unlock()
+	if !ok {
+		http.Error(rw, "unknown or already-answered question", http.StatusNotFound)
+		return
+	}
+
+	select {
+	case answerCh <- body.Answer:
+	default:
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -22,6 +22,9 @@ func NewZenityHandler(timeout time.Duration) *ZenityHandler {
 	}
 }
 
+// Name implements AskBackend.
+func (z *ZenityHandler) Name() string { return "zenity" }
+
 // AskQuestion shows a zenity dialog to ask the user a question and returns the answer
 func (z *ZenityHandler) AskQuestion(parentCtx context.Context, questionID, question, contextInfo string) (string, error) {
 	// Build the dialog text